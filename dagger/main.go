@@ -8,19 +8,312 @@
 //   dagger call frontend-test --source=..
 //   dagger call ci --source=..
 //   dagger call build-images --source=..
+//   dagger call ci --ref=github.com/org/repo#main --exclude=backend/target --exclude=frontend/node_modules
+//   dagger call dev --source=.. up --ports 3000:80
 
 package main
 
 import (
 	"context"
 	"dagger/chessot/internal/dagger"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 type Chessot struct{}
 
-// BackendTest exécute les tests backend avec Maven et PostgreSQL
-func (m *Example) BackendTest(ctx context.Context, source *dagger.Directory) (string, error) {
+// JobResult est le résultat d'un job de la pipeline CI (test, lint, ...).
+type JobResult struct {
+	Job      string
+	Status   string // "passed" ou "failed"
+	Duration string
+	Stdout   string
+	Error    string `json:",omitempty"`
+}
+
+// CIReport agrège les résultats de tous les jobs d'une exécution CI.
+type CIReport struct {
+	Jobs    []JobResult
+	Success bool
+}
+
+// ciJob associe un nom de job au container (non évalué) qui le produit.
+type ciJob struct {
+	name      string
+	container *dagger.Container
+}
+
+// runJobsConcurrently exécute chaque job dans sa propre goroutine, liées à un
+// contexte partagé qui s'annule dès le premier échec, et collecte les
+// résultats dans l'ordre des jobs fournis.
+func runJobsConcurrently(ctx context.Context, jobs []ciJob) ([]JobResult, error) {
+	results := make([]JobResult, len(jobs))
+
+	g, gctx := errgroup.WithContext(ctx)
+	for i, job := range jobs {
+		i, job := i, job
+		g.Go(func() error {
+			start := time.Now()
+			stdout, err := job.container.Stdout(gctx)
+			results[i] = JobResult{
+				Job:      job.name,
+				Duration: time.Since(start).String(),
+				Stdout:   stdout,
+			}
+			if err != nil {
+				results[i].Status = "failed"
+				results[i].Error = err.Error()
+				return fmt.Errorf("%s: %w", job.name, err)
+			}
+			results[i].Status = "passed"
+			return nil
+		})
+	}
+
+	return results, g.Wait()
+}
+
+// parseGitRef sépare un ref de la forme "github.com/org/repo#branch" en son
+// dépôt et sa référence Git. Sans "#", hasRef vaut false et l'appelant doit
+// résoudre la référence par défaut du dépôt (HEAD).
+func parseGitRef(ref string) (repo string, gitRef string, hasRef bool) {
+	return strings.Cut(ref, "#")
+}
+
+// prepareSource résout la source d'un pipeline : le répertoire local fourni
+// via source, ou à défaut un ref Git distant (ex. "github.com/org/repo#branch")
+// résolu via dag.Git(). Les filtres include/exclude sont ensuite appliqués pour
+// exclure du répertoire final les artefacts volumineux (backend/target,
+// frontend/node_modules, .git, ...) qui invalideraient inutilement le cache.
+func (m *Chessot) prepareSource(ctx context.Context, source *dagger.Directory, ref string, include []string, exclude []string) (*dagger.Directory, error) {
+	if source == nil {
+		if ref == "" {
+			return nil, fmt.Errorf("either source or ref must be provided")
+		}
+
+		repo, gitRef, hasRef := parseGitRef(ref)
+		git := dag.Git(repo)
+		if hasRef {
+			source = git.Ref(gitRef).Tree()
+		} else {
+			source = git.Head().Tree()
+		}
+	}
+
+	if len(include) == 0 && len(exclude) == 0 {
+		return source, nil
+	}
+
+	return dag.Directory().WithDirectory("/", source, dagger.DirectoryWithDirectoryOpts{
+		Include: include,
+		Exclude: exclude,
+	}), nil
+}
+
+// MavenToolchain configure un environnement Maven réutilisable (version de
+// JDK, MAVEN_OPTS, services liés) et construit paresseusement le container
+// correspondant. Chaque méthode With* renvoie le toolchain pour permettre le
+// chaînage.
+type MavenToolchain struct {
+	// +private
+	Source *dagger.Directory
+	// +private
+	JDKVersion string
+	// +private
+	MavenOpts string
+	// +private
+	ServiceNames []string
+	// +private
+	Services []*dagger.Service
+}
+
+// MavenToolchain crée un toolchain Maven pour le répertoire backend/ de source,
+// avec le JDK 21 par défaut.
+func (m *Chessot) MavenToolchain(source *dagger.Directory) *MavenToolchain {
+	return &MavenToolchain{
+		Source:     source,
+		JDKVersion: "21",
+	}
+}
+
+// WithJDK change la version de JDK utilisée par le toolchain.
+func (t *MavenToolchain) WithJDK(version string) *MavenToolchain {
+	t.JDKVersion = version
+	return t
+}
+
+// WithMavenOpts définit la variable d'environnement MAVEN_OPTS.
+func (t *MavenToolchain) WithMavenOpts(opts string) *MavenToolchain {
+	t.MavenOpts = opts
+	return t
+}
+
+// WithService lie un service (ex. PostgreSQL) au container sous le nom donné.
+func (t *MavenToolchain) WithService(name string, service *dagger.Service) *MavenToolchain {
+	t.ServiceNames = append(t.ServiceNames, name)
+	t.Services = append(t.Services, service)
+	return t
+}
+
+// Container construit le container Maven configuré par le toolchain.
+func (t *MavenToolchain) Container() *dagger.Container {
+	ctr := dag.Container().
+		From(fmt.Sprintf("maven:3.9-eclipse-temurin-%s", t.JDKVersion)).
+		WithDirectory("/app", t.Source.Directory("backend")).
+		WithWorkdir("/app").
+		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache"))
+
+	for i, name := range t.ServiceNames {
+		ctr = ctr.WithServiceBinding(name, t.Services[i])
+	}
+	if t.MavenOpts != "" {
+		ctr = ctr.WithEnvVariable("MAVEN_OPTS", t.MavenOpts)
+	}
+
+	return ctr
+}
+
+// Verify exécute "mvn verify".
+func (t *MavenToolchain) Verify(ctx context.Context) (string, error) {
+	return t.Container().WithExec([]string{"mvn", "verify", "-B"}).Stdout(ctx)
+}
+
+// Package exécute "mvn package" et renvoie le JAR produit.
+func (t *MavenToolchain) Package(ctx context.Context) *dagger.File {
+	return t.Container().
+		WithExec([]string{"mvn", "package", "-DskipTests", "-B"}).
+		File("/app/target/app-0.0.1-SNAPSHOT.jar")
+}
+
+// DependencyCheck exécute l'OWASP Dependency Check plugin Maven.
+func (t *MavenToolchain) DependencyCheck(ctx context.Context) (string, error) {
+	return t.Container().
+		WithExec([]string{"mvn", "org.owasp:dependency-check-maven:check", "-B"}).
+		Stdout(ctx)
+}
+
+// NodeToolchain configure un environnement Node réutilisable (version de
+// Node, gestionnaire de paquets) et construit paresseusement le container
+// correspondant. Chaque méthode With* renvoie le toolchain pour permettre le
+// chaînage.
+type NodeToolchain struct {
+	// +private
+	Source *dagger.Directory
+	// +private
+	NodeVersion string
+	// +private
+	PackageManager string
+}
+
+// NodeToolchain crée un toolchain Node pour le répertoire frontend/ de source,
+// avec Node 22 et npm par défaut.
+func (m *Chessot) NodeToolchain(source *dagger.Directory) *NodeToolchain {
+	return &NodeToolchain{
+		Source:         source,
+		NodeVersion:    "22",
+		PackageManager: "npm",
+	}
+}
+
+// WithNodeVersion change la version de Node utilisée par le toolchain.
+func (t *NodeToolchain) WithNodeVersion(version string) *NodeToolchain {
+	t.NodeVersion = version
+	return t
+}
+
+// WithPackageManager change le gestionnaire de paquets ("npm", "pnpm" ou "yarn").
+func (t *NodeToolchain) WithPackageManager(manager string) *NodeToolchain {
+	t.PackageManager = manager
+	return t
+}
+
+// installCommand renvoie la commande d'installation des dépendances adaptée
+// au gestionnaire de paquets configuré.
+func (t *NodeToolchain) installCommand() []string {
+	switch t.PackageManager {
+	case "pnpm":
+		return []string{"pnpm", "install", "--frozen-lockfile"}
+	case "yarn":
+		return []string{"yarn", "install", "--frozen-lockfile"}
+	default:
+		return []string{"npm", "ci"}
+	}
+}
+
+// baseContainer construit le container Node configuré par le toolchain, avant
+// installation des dépendances.
+func (t *NodeToolchain) baseContainer() *dagger.Container {
+	ctr := dag.Container().
+		From(fmt.Sprintf("node:%s-alpine", t.NodeVersion)).
+		WithDirectory("/app", t.Source.Directory("frontend")).
+		WithWorkdir("/app").
+		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
+		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache"))
+
+	if t.PackageManager == "pnpm" || t.PackageManager == "yarn" {
+		ctr = ctr.WithExec([]string{"corepack", "enable"})
+	}
+
+	return ctr
+}
+
+// Install installe les dépendances du frontend.
+func (t *NodeToolchain) Install() *dagger.Container {
+	return t.baseContainer().WithExec(t.installCommand())
+}
+
+// Container renvoie le container Node avec les dépendances installées.
+func (t *NodeToolchain) Container() *dagger.Container {
+	return t.Install()
+}
+
+// Lint exécute le linting (ESLint).
+func (t *NodeToolchain) Lint(ctx context.Context) (string, error) {
+	return t.Install().WithExec([]string{t.PackageManager, "run", "lint"}).Stdout(ctx)
+}
+
+// TypeCheck exécute la vérification de types TypeScript.
+func (t *NodeToolchain) TypeCheck(ctx context.Context) (string, error) {
+	return t.Install().WithExec([]string{t.PackageManager, "run", "type-check"}).Stdout(ctx)
+}
+
+// Test exécute les tests avec couverture.
+func (t *NodeToolchain) Test(ctx context.Context) (string, error) {
+	return t.Install().WithExec([]string{t.PackageManager, "run", "test:coverage"}).Stdout(ctx)
+}
+
+// Build compile les assets de production et renvoie le répertoire produit.
+func (t *NodeToolchain) Build(ctx context.Context) *dagger.Directory {
+	return t.Install().
+		WithEnvVariable("VITE_API_URL", "/api").
+		WithExec([]string{t.PackageManager, "run", "build"}).
+		Directory("/app/dist")
+}
+
+// auditCommand renvoie la commande d'audit de sécurité adaptée au
+// gestionnaire de paquets configuré: Yarn classic utilise --level là où
+// npm/pnpm utilisent --audit-level.
+func (t *NodeToolchain) auditCommand() []string {
+	switch t.PackageManager {
+	case "yarn":
+		return []string{"yarn", "audit", "--level", "high"}
+	default:
+		return []string{t.PackageManager, "audit", "--audit-level=high"}
+	}
+}
+
+// Audit exécute l'audit de sécurité des dépendances.
+func (t *NodeToolchain) Audit(ctx context.Context) (string, error) {
+	return t.Install().WithExec(t.auditCommand()).Stdout(ctx)
+}
+
+// backendTestContainer construit (sans l'évaluer) le container qui exécute
+// les tests backend avec Maven et PostgreSQL.
+func (m *Chessot) backendTestContainer(source *dagger.Directory) *dagger.Container {
 	// Service PostgreSQL pour les tests
 	postgres := dag.Container().
 		From("postgres:16-alpine").
@@ -30,93 +323,149 @@ func (m *Example) BackendTest(ctx context.Context, source *dagger.Directory) (st
 		WithExposedPort(5432).
 		AsService()
 
-	// Container Maven avec JDK 21
-	backend := dag.Container().
-		From("maven:3.9-eclipse-temurin-21").
-		WithDirectory("/app", source.Directory("backend")).
-		WithWorkdir("/app").
-		WithServiceBinding("postgres", postgres).
+	return m.MavenToolchain(source).
+		WithService("postgres", postgres).
+		Container().
 		WithEnvVariable("SPRING_DATASOURCE_URL", "jdbc:postgresql://postgres:5432/testdb").
 		WithEnvVariable("SPRING_DATASOURCE_USERNAME", "test").
 		WithEnvVariable("SPRING_DATASOURCE_PASSWORD", "test").
-		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache")).
 		WithExec([]string{"mvn", "verify", "-B"})
+}
 
-	return backend.Stdout(ctx)
+// BackendTest exécute les tests backend avec Maven et PostgreSQL
+func (m *Chessot) BackendTest(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+	return m.backendTestContainer(src).Stdout(ctx)
 }
 
 // BackendBuild compile le backend et produit le JAR
-func (m *Example) BackendBuild(ctx context.Context, source *dagger.Directory) *dagger.File {
-	backend := dag.Container().
-		From("maven:3.9-eclipse-temurin-21").
-		WithDirectory("/app", source.Directory("backend")).
-		WithWorkdir("/app").
-		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache")).
-		WithExec([]string{"mvn", "package", "-DskipTests", "-B"})
-
-	return backend.File("/app/target/app-0.0.1-SNAPSHOT.jar")
+func (m *Chessot) BackendBuild(ctx context.Context, source *dagger.Directory) *dagger.File {
+	return m.MavenToolchain(source).Package(ctx)
 }
 
-// FrontendTest exécute les tests frontend (lint, type-check, tests)
-func (m *Example) FrontendTest(ctx context.Context, source *dagger.Directory) (string, error) {
-	frontend := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithExec([]string{"npm", "ci"}).
+// frontendTestContainer construit (sans l'évaluer) le container qui exécute
+// lint, type-check et les tests de couverture du frontend.
+func (m *Chessot) frontendTestContainer(source *dagger.Directory) *dagger.Container {
+	return m.NodeToolchain(source).
+		Install().
 		WithExec([]string{"npm", "run", "lint"}).
 		WithExec([]string{"npm", "run", "type-check"}).
 		WithExec([]string{"npm", "run", "test:coverage"})
+}
 
-	return frontend.Stdout(ctx)
+// FrontendTest exécute les tests frontend (lint, type-check, tests)
+func (m *Chessot) FrontendTest(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+	return m.frontendTestContainer(src).Stdout(ctx)
 }
 
 // FrontendBuild compile le frontend et produit les assets statiques
-func (m *Example) FrontendBuild(ctx context.Context, source *dagger.Directory) *dagger.Directory {
-	frontend := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithEnvVariable("VITE_API_URL", "/api").
-		WithExec([]string{"npm", "ci"}).
-		WithExec([]string{"npm", "run", "build"})
+func (m *Chessot) FrontendBuild(ctx context.Context, source *dagger.Directory) *dagger.Directory {
+	return m.NodeToolchain(source).Build(ctx)
+}
 
-	return frontend.Directory("/app/dist")
+// ciJobs construit la liste des jobs (backend-test, frontend-test, lint) que
+// CI exécute en parallèle, chacun lié à un *dagger.Container évalué paresseusement.
+func (m *Chessot) ciJobs(source *dagger.Directory) []ciJob {
+	return []ciJob{
+		{"backend-test", m.backendTestContainer(source)},
+		{"frontend-test", m.frontendTestContainer(source)},
+		{"lint", m.lintContainer(source)},
+	}
 }
 
-// CI exécute la pipeline CI complète (backend + frontend)
-func (m *Example) Ci(ctx context.Context, source *dagger.Directory) (string, error) {
-	// Exécuter les tests en parallèle
-	backendResult, backendErr := m.BackendTest(ctx, source)
-	frontendResult, frontendErr := m.FrontendTest(ctx, source)
+// Ci exécute la pipeline CI complète (backend, frontend, lint) en parallèle
+// grâce à errgroup, et renvoie un résumé lisible. Le premier job en échec
+// annule le contexte des autres.
+func (m *Chessot) Ci(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+
+	results, err := runJobsConcurrently(ctx, m.ciJobs(src))
 
 	output := "=== CI Pipeline Results ===\n\n"
+	for _, result := range results {
+		output += fmt.Sprintf("--- %s (%s) ---\n", result.Job, result.Duration)
+		if result.Status == "failed" {
+			output += fmt.Sprintf("❌ FAILED: %s\n", result.Error)
+			continue
+		}
+		output += "✅ PASSED\n"
+		output += result.Stdout + "\n"
+	}
 
-	output += "--- Backend Tests ---\n"
-	if backendErr != nil {
-		output += fmt.Sprintf("❌ FAILED: %v\n", backendErr)
-		return output, backendErr
+	return output, err
+}
+
+// CiReport exécute la même pipeline que Ci mais renvoie le CIReport structuré
+// au format JSON, pour être consommé par un outillage externe.
+func (m *Chessot) CiReport(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
 	}
-	output += "✅ PASSED\n"
-	output += backendResult + "\n"
 
-	output += "--- Frontend Tests ---\n"
-	if frontendErr != nil {
-		output += fmt.Sprintf("❌ FAILED: %v\n", frontendErr)
-		return output, frontendErr
+	results, err := runJobsConcurrently(ctx, m.ciJobs(src))
+
+	report := CIReport{Jobs: results, Success: err == nil}
+	data, marshalErr := json.MarshalIndent(report, "", "  ")
+	if marshalErr != nil {
+		return "", marshalErr
 	}
-	output += "✅ PASSED\n"
-	output += frontendResult + "\n"
 
-	return output, nil
+	return string(data), err
 }
 
 // BuildBackendImage construit l'image Docker du backend
-func (m *Example) BuildBackendImage(ctx context.Context, source *dagger.Directory) *dagger.Container {
+func (m *Chessot) BuildBackendImage(ctx context.Context, source *dagger.Directory) *dagger.Container {
 	// D'abord, construire le JAR
 	jar := m.BackendBuild(ctx, source)
 
@@ -130,7 +479,7 @@ func (m *Example) BuildBackendImage(ctx context.Context, source *dagger.Director
 }
 
 // BuildFrontendImage construit l'image Docker du frontend avec Nginx
-func (m *Example) BuildFrontendImage(ctx context.Context, source *dagger.Directory) *dagger.Container {
+func (m *Chessot) BuildFrontendImage(ctx context.Context, source *dagger.Directory) *dagger.Container {
 	// D'abord, construire les assets
 	dist := m.FrontendBuild(ctx, source)
 
@@ -141,38 +490,168 @@ func (m *Example) BuildFrontendImage(ctx context.Context, source *dagger.Directo
 		WithExposedPort(80)
 }
 
-// BuildImages construit les deux images Docker (backend + frontend)
-func (m *Example) BuildImages(ctx context.Context, source *dagger.Directory) (string, error) {
-	backendImage := m.BuildBackendImage(ctx, source)
-	frontendImage := m.BuildFrontendImage(ctx, source)
+// publishTarget décrit une image du dépôt (backend ou frontend) à publier.
+type publishTarget struct {
+	name       string
+	dockerfile string
+}
+
+var publishTargets = []publishTarget{
+	{"backend", "docker/Dockerfile.backend"},
+	{"frontend", "docker/Dockerfile.frontend"},
+}
+
+// buildTargetImage construit l'image Docker d'une cible (backend ou
+// frontend) pour une plateforme donnée, à partir de son Dockerfile. Point
+// d'entrée partagé par BuildImages et Publish pour éviter deux chemins de
+// build disjoints.
+func buildTargetImage(src *dagger.Directory, target publishTarget, platform string, buildArgs []dagger.BuildArg) *dagger.Container {
+	return src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+		Dockerfile: target.dockerfile,
+		Platform:   dagger.Platform(platform),
+		BuildArgs:  buildArgs,
+	})
+}
 
-	// Exporter les images localement
-	_, err := backendImage.Export(ctx, "app-backend.tar")
+// BuildImages construit les images Docker backend et frontend à partir de
+// leurs Dockerfiles et vérifie qu'elles se construisent correctement, sans
+// les publier. Utile en local ou dans une CI qui n'a pas encore de registre
+// configuré. Pour publier les images sur un registre (multi-arch, signature
+// cosign, cache), voir Publish.
+func (m *Chessot) BuildImages(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
 	if err != nil {
-		return "", fmt.Errorf("failed to export backend image: %w", err)
+		return "", err
 	}
 
-	_, err = frontendImage.Export(ctx, "app-frontend.tar")
+	output := "=== Build Images ===\n\n"
+	for _, target := range publishTargets {
+		image := buildTargetImage(src, target, "linux/amd64", nil)
+		if _, err := image.Sync(ctx); err != nil {
+			return output, fmt.Errorf("failed to build %s image: %w", target.name, err)
+		}
+		output += fmt.Sprintf("✅ %s image built successfully\n", target.name)
+	}
+
+	return output, nil
+}
+
+// cosignSign signe le digest d'une image publiée avec une clé cosign et
+// renvoie la sortie de la commande de signature.
+func cosignSign(ctx context.Context, imageRef string, cosignKey *dagger.Secret) (string, error) {
+	return dag.Container().
+		From("gcr.io/projectsigstore/cosign:v2.2.4").
+		WithSecretVariable("COSIGN_PRIVATE_KEY", cosignKey).
+		WithExec([]string{"sh", "-c", `echo "$COSIGN_PRIVATE_KEY" > /tmp/cosign.key`}).
+		WithExec([]string{"cosign", "sign", "--key", "/tmp/cosign.key", "--yes", imageRef}).
+		Stdout(ctx)
+}
+
+// Publish construit les images backend et frontend pour chaque plateforme
+// demandée, assemble un manifest multi-arch et les publie sur le registre.
+// Si cosignKey est fourni, le digest publié est signé avec cosign. Le cache
+// de layers inter-runs est géré en mode inline uniquement : si cacheTo est
+// fourni, les images sont construites avec BUILDKIT_INLINE_CACHE=1 (le
+// manifest publié embarque ses propres métadonnées de cache) et publiées en
+// plus sous ce tag. dagger.DirectoryDockerBuildOpts n'expose pas d'import de
+// cache externe (--cache-from) à ce niveau d'API : réutiliser le cache d'un
+// tag publié par un run précédent n'est donc pas supporté ici.
+func (m *Chessot) Publish(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	registry string,
+	repository string,
+	// +optional
+	tags []string,
+	// +optional
+	platforms []string,
+	// +optional
+	registryUsername string,
+	// +optional
+	registryAuth *dagger.Secret,
+	// +optional
+	cosignKey *dagger.Secret,
+	// +optional
+	cacheTo string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, nil, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to export frontend image: %w", err)
+		return "", err
 	}
 
-	return "✅ Images Docker construites avec succès:\n  - app-backend.tar\n  - app-frontend.tar", nil
+	if len(platforms) == 0 {
+		platforms = []string{"linux/amd64", "linux/arm64"}
+	}
+	if len(tags) == 0 {
+		tags = []string{"latest"}
+	}
+
+	var buildArgs []dagger.BuildArg
+	if cacheTo != "" {
+		buildArgs = append(buildArgs, dagger.BuildArg{Name: "BUILDKIT_INLINE_CACHE", Value: "1"})
+	}
+
+	output := "=== Publish ===\n\n"
+
+	for _, target := range publishTargets {
+		variants := make([]*dagger.Container, 0, len(platforms))
+		for _, platform := range platforms {
+			variants = append(variants, buildTargetImage(src, target, platform, buildArgs))
+		}
+
+		publisher := dag.Container()
+		if registryAuth != nil {
+			publisher = publisher.WithRegistryAuth(registry, registryUsername, registryAuth)
+		}
+
+		refs := []string{}
+		for _, tag := range tags {
+			refs = append(refs, fmt.Sprintf("%s/%s-%s:%s", registry, repository, target.name, tag))
+		}
+		if cacheTo != "" {
+			refs = append(refs, fmt.Sprintf("%s/%s-%s:%s", registry, repository, target.name, cacheTo))
+		}
+
+		for _, imageRef := range refs {
+			digest, err := publisher.Publish(ctx, imageRef, dagger.ContainerPublishOpts{
+				PlatformVariants: variants,
+			})
+			if err != nil {
+				return output, fmt.Errorf("failed to publish %s: %w", imageRef, err)
+			}
+			output += fmt.Sprintf("✅ Published %s\n", digest)
+
+			if cosignKey != nil {
+				if _, err := cosignSign(ctx, digest, cosignKey); err != nil {
+					return output, fmt.Errorf("failed to sign %s: %w", digest, err)
+				}
+				output += fmt.Sprintf("🔏 Signed %s\n", digest)
+			}
+		}
+	}
+
+	return output, nil
 }
 
 // SecurityCheck exécute les vérifications de sécurité
-func (m *Example) SecurityCheck(ctx context.Context, source *dagger.Directory) (string, error) {
+func (m *Chessot) SecurityCheck(ctx context.Context, source *dagger.Directory) (string, error) {
 	output := "=== Security Check ===\n\n"
 
 	// OWASP Dependency Check pour le backend
-	backendSecurity := dag.Container().
-		From("maven:3.9-eclipse-temurin-21").
-		WithDirectory("/app", source.Directory("backend")).
-		WithWorkdir("/app").
-		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache")).
-		WithExec([]string{"mvn", "org.owasp:dependency-check-maven:check", "-B"})
-
-	_, err := backendSecurity.Stdout(ctx)
+	_, err := m.MavenToolchain(source).DependencyCheck(ctx)
 	if err != nil {
 		output += fmt.Sprintf("⚠️ Backend OWASP check: %v\n", err)
 	} else {
@@ -180,16 +659,7 @@ func (m *Example) SecurityCheck(ctx context.Context, source *dagger.Directory) (
 	}
 
 	// npm audit pour le frontend
-	frontendSecurity := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithExec([]string{"npm", "ci"}).
-		WithExec([]string{"npm", "audit", "--audit-level=high"})
-
-	_, err = frontendSecurity.Stdout(ctx)
+	_, err = m.NodeToolchain(source).Audit(ctx)
 	if err != nil {
 		output += fmt.Sprintf("⚠️ Frontend npm audit: %v\n", err)
 	} else {
@@ -199,21 +669,17 @@ func (m *Example) SecurityCheck(ctx context.Context, source *dagger.Directory) (
 	return output, nil
 }
 
+// lintContainer construit (sans l'évaluer) le container qui exécute ESLint
+// sur le frontend.
+func (m *Chessot) lintContainer(source *dagger.Directory) *dagger.Container {
+	return m.NodeToolchain(source).Install().WithExec([]string{"npm", "run", "lint"})
+}
+
 // Lint exécute le linting sur le backend et le frontend
-func (m *Example) Lint(ctx context.Context, source *dagger.Directory) (string, error) {
+func (m *Chessot) Lint(ctx context.Context, source *dagger.Directory) (string, error) {
 	output := "=== Lint ===\n\n"
 
-	// Lint frontend avec ESLint
-	frontendLint := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithExec([]string{"npm", "ci"}).
-		WithExec([]string{"npm", "run", "lint"})
-
-	_, err := frontendLint.Stdout(ctx)
+	_, err := m.lintContainer(source).Stdout(ctx)
 	if err != nil {
 		output += fmt.Sprintf("❌ Frontend lint failed: %v\n", err)
 		return output, err
@@ -224,18 +690,33 @@ func (m *Example) Lint(ctx context.Context, source *dagger.Directory) (string, e
 }
 
 // All exécute toute la pipeline: CI + Security + Build images
-func (m *Example) All(ctx context.Context, source *dagger.Directory) (string, error) {
+func (m *Chessot) All(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+
 	output := "🚀 Running full pipeline...\n\n"
 
 	// CI
-	ciResult, err := m.Ci(ctx, source)
+	ciResult, err := m.Ci(ctx, src, "", nil, nil)
 	if err != nil {
 		return output + ciResult, err
 	}
 	output += ciResult + "\n"
 
 	// Build images
-	buildResult, err := m.BuildImages(ctx, source)
+	buildResult, err := m.BuildImages(ctx, src, "", nil, nil)
 	if err != nil {
 		return output + buildResult, err
 	}
@@ -247,30 +728,42 @@ func (m *Example) All(ctx context.Context, source *dagger.Directory) (string, er
 
 // GithubActionsCI reproduit exactement le workflow GitHub Actions ci.yml
 // Cette fonction teste l'ensemble de la pipeline CI/CD comme GitHub Actions le ferait
-func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory) (string, error) {
+func (m *Chessot) GithubActionsCI(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+
 	output := "=== GitHub Actions CI Workflow ===\n\n"
 
 	// ============================================
-	// Job 1: Backend Tests
+	// Job 1 & 2: Backend Tests + Frontend Tests (en parallèle)
 	// ============================================
-	output += "📦 Job: backend-test\n"
-	_, backendErr := m.BackendTest(ctx, source)
-	if backendErr != nil {
-		output += fmt.Sprintf("❌ Backend tests failed: %v\n", backendErr)
-		return output, backendErr
+	results, err := runJobsConcurrently(ctx, []ciJob{
+		{"backend-test", m.backendTestContainer(src)},
+		{"frontend-test", m.frontendTestContainer(src)},
+	})
+	for _, result := range results {
+		output += fmt.Sprintf("📦 Job: %s\n", result.Job)
+		if result.Status == "failed" {
+			output += fmt.Sprintf("❌ %s failed: %s\n", result.Job, result.Error)
+			continue
+		}
+		output += fmt.Sprintf("✅ %s passed\n\n", result.Job)
 	}
-	output += "✅ Backend tests passed\n\n"
-
-	// ============================================
-	// Job 2: Frontend Tests
-	// ============================================
-	output += "📦 Job: frontend-test\n"
-	_, frontendErr := m.FrontendTest(ctx, source)
-	if frontendErr != nil {
-		output += fmt.Sprintf("❌ Frontend tests failed: %v\n", frontendErr)
-		return output, frontendErr
+	if err != nil {
+		return output, err
 	}
-	output += "✅ Frontend tests passed\n\n"
 
 	// ============================================
 	// Job 3: Build Verification
@@ -279,8 +772,8 @@ func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory)
 
 	// Build backend
 	output += "  - Building backend JAR...\n"
-	jar := m.BackendBuild(ctx, source)
-	_, err := jar.Contents(ctx)
+	jar := m.BackendBuild(ctx, src)
+	_, err = jar.Contents(ctx)
 	if err != nil {
 		output += fmt.Sprintf("❌ Backend build failed: %v\n", err)
 		return output, err
@@ -289,7 +782,7 @@ func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory)
 
 	// Build frontend
 	output += "  - Building frontend dist...\n"
-	dist := m.FrontendBuild(ctx, source)
+	dist := m.FrontendBuild(ctx, src)
 	_, err = dist.Entries(ctx)
 	if err != nil {
 		output += fmt.Sprintf("❌ Frontend build failed: %v\n", err)
@@ -304,7 +797,7 @@ func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory)
 
 	// Build backend Docker image avec Dockerfile depuis racine
 	output += "  - Building backend Docker image...\n"
-	backendImage := source.DockerBuild(dagger.DirectoryDockerBuildOpts{
+	backendImage := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
 		Dockerfile: "docker/Dockerfile.backend",
 	})
 	_, err = backendImage.Sync(ctx)
@@ -316,7 +809,7 @@ func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory)
 
 	// Build frontend Docker image avec Dockerfile depuis racine
 	output += "  - Building frontend Docker image...\n"
-	frontendImage := source.DockerBuild(dagger.DirectoryDockerBuildOpts{
+	frontendImage := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
 		Dockerfile: "docker/Dockerfile.frontend",
 	})
 	_, err = frontendImage.Sync(ctx)
@@ -331,7 +824,7 @@ func (m *Example) GithubActionsCI(ctx context.Context, source *dagger.Directory)
 }
 
 // Serve démarre l'application complète avec PostgreSQL pour le développement
-func (m *Example) Serve(ctx context.Context, source *dagger.Directory) (*dagger.Service, error) {
+func (m *Chessot) Serve(ctx context.Context, source *dagger.Directory) (*dagger.Service, error) {
 	// Service PostgreSQL
 	postgres := dag.Container().
 		From("postgres:16-alpine").
@@ -358,9 +851,128 @@ func (m *Example) Serve(ctx context.Context, source *dagger.Directory) (*dagger.
 	return backend, nil
 }
 
+// devProxyConf configure Nginx pour exposer frontend et backend derrière un
+// unique port, afin que `dagger call dev up` n'ait besoin de publier qu'un
+// seul port.
+const devProxyConf = `server {
+    listen 80;
+
+    location /api/ {
+        proxy_pass http://backend:8080/api/;
+    }
+
+    location / {
+        proxy_pass http://frontend:5173/;
+    }
+}
+`
+
+// waitForPostgres bloque jusqu'à ce que le service PostgreSQL accepte les
+// connexions, pour que les callers de Dev ne démarrent pas une course avec
+// l'initialisation de la base. Abandonne après 60s avec une erreur explicite
+// plutôt que de bloquer indéfiniment si Postgres ne démarre jamais.
+func waitForPostgres(ctx context.Context, postgres *dagger.Service) error {
+	_, err := dag.Container().
+		From("postgres:16-alpine").
+		WithServiceBinding("postgres", postgres).
+		WithExec([]string{"sh", "-c", "timeout 60 sh -c 'until pg_isready -h postgres -U appuser; do sleep 1; done'"}).
+		Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("postgres did not become ready within 60s: %w", err)
+	}
+	return nil
+}
+
+// Dev démarre une stack de développement complète : PostgreSQL avec
+// persistance des données, backend en hot-reload via spring-boot:run
+// (Spring DevTools) et frontend en hot-reload via le serveur de dev Vite, le
+// tout derrière un reverse-proxy Nginx exposé sur un seul port.
+func (m *Chessot) Dev(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	pgDataVolume *dagger.CacheVolume,
+) (*dagger.Service, error) {
+	src, err := m.prepareSource(ctx, source, ref, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if pgDataVolume == nil {
+		pgDataVolume = dag.CacheVolume("pg-data")
+	}
+
+	// PostgreSQL avec données persistées entre les redémarrages
+	postgres := dag.Container().
+		From("postgres:16-alpine").
+		WithMountedCache("/var/lib/postgresql/data", pgDataVolume).
+		WithEnvVariable("POSTGRES_DB", "appdb").
+		WithEnvVariable("POSTGRES_USER", "appuser").
+		WithEnvVariable("POSTGRES_PASSWORD", "apppassword").
+		WithExposedPort(5432).
+		AsService()
+
+	if err := waitForPostgres(ctx, postgres); err != nil {
+		return nil, err
+	}
+
+	// Backend en hot-reload avec Spring DevTools, sans build de JAR
+	backend := m.MavenToolchain(src).
+		WithService("postgres", postgres).
+		Container().
+		WithEnvVariable("SPRING_DATASOURCE_URL", "jdbc:postgresql://postgres:5432/appdb").
+		WithEnvVariable("SPRING_DATASOURCE_USERNAME", "appuser").
+		WithEnvVariable("SPRING_DATASOURCE_PASSWORD", "apppassword").
+		WithExposedPort(8080).
+		WithExec([]string{"mvn", "spring-boot:run"}).
+		AsService()
+
+	// Frontend en hot-reload avec le serveur de dev Vite. VITE_API_URL reste
+	// relative (/api), comme pour FrontendBuild: cette valeur est servie au
+	// navigateur de l'utilisateur, qui ne peut pas résoudre le hostname
+	// "backend" du service-binding Dagger — les requêtes doivent repasser
+	// par le reverse-proxy Nginx sur le port exposé.
+	frontend := m.NodeToolchain(src).
+		Install().
+		WithServiceBinding("backend", backend).
+		WithEnvVariable("VITE_API_URL", "/api").
+		WithExposedPort(5173).
+		WithExec([]string{"npm", "run", "dev", "--", "--host", "0.0.0.0"}).
+		AsService()
+
+	// Reverse-proxy Nginx exposant frontend et backend sur un seul port
+	proxy := dag.Container().
+		From("nginx:alpine").
+		WithNewFile("/etc/nginx/conf.d/default.conf", devProxyConf).
+		WithServiceBinding("frontend", frontend).
+		WithServiceBinding("backend", backend).
+		WithExposedPort(80).
+		AsService()
+
+	return proxy, nil
+}
+
 // GithubActionsSecurity reproduit exactement le workflow GitHub Actions security.yml
 // Cette fonction teste les vérifications de sécurité comme GitHub Actions le ferait
-func (m *Example) GithubActionsSecurity(ctx context.Context, source *dagger.Directory) (string, error) {
+func (m *Chessot) GithubActionsSecurity(
+	ctx context.Context,
+	// +optional
+	source *dagger.Directory,
+	// +optional
+	ref string,
+	// +optional
+	include []string,
+	// +optional
+	exclude []string,
+) (string, error) {
+	src, err := m.prepareSource(ctx, source, ref, include, exclude)
+	if err != nil {
+		return "", err
+	}
+
 	output := "=== GitHub Actions Security Workflow ===\n\n"
 
 	// ============================================
@@ -370,14 +982,7 @@ func (m *Example) GithubActionsSecurity(ctx context.Context, source *dagger.Dire
 
 	// Backend - OWASP Dependency Check
 	output += "  - Running OWASP Dependency Check...\n"
-	owaspCheck := dag.Container().
-		From("maven:3.9-eclipse-temurin-21").
-		WithDirectory("/app", source.Directory("backend")).
-		WithWorkdir("/app").
-		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache")).
-		WithExec([]string{"mvn", "org.owasp:dependency-check-maven:check", "-B"})
-
-	_, err := owaspCheck.Stdout(ctx)
+	_, err = m.MavenToolchain(src).DependencyCheck(ctx)
 	if err != nil {
 		output += fmt.Sprintf("⚠️  OWASP check completed with warnings (this is normal)\n")
 	} else {
@@ -386,16 +991,7 @@ func (m *Example) GithubActionsSecurity(ctx context.Context, source *dagger.Dire
 
 	// Frontend - npm audit
 	output += "  - Running npm audit...\n"
-	npmAudit := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithExec([]string{"npm", "ci"}).
-		WithExec([]string{"npm", "audit", "--audit-level=high"})
-
-	_, err = npmAudit.Stdout(ctx)
+	_, err = m.NodeToolchain(src).Audit(ctx)
 	if err != nil {
 		output += fmt.Sprintf("⚠️  npm audit completed with warnings (this is normal)\n")
 	} else {
@@ -408,30 +1004,16 @@ func (m *Example) GithubActionsSecurity(ctx context.Context, source *dagger.Dire
 	// ============================================
 	output += "📦 Job: trivy\n"
 
-	// Build backend Docker image depuis la racine
-	output += "  - Building backend Docker image for scanning...\n"
-	backendImage := source.DockerBuild(dagger.DirectoryDockerBuildOpts{
-		Dockerfile: "docker/Dockerfile.backend",
-	})
-	_, err = backendImage.Sync(ctx)
+	// Scan avec Trivy l'image backend construite depuis son Dockerfile,
+	// exportée en tarball pour que Trivy puisse la lire sans registre.
+	output += "  - Building backend Docker image and running Trivy scanner...\n"
+	trivyReport := m.TrivyScanReport(ctx, src, "table", "", false)
+	trivyOutput, err := trivyReport.Contents(ctx)
 	if err != nil {
-		output += fmt.Sprintf("❌ Backend Docker image build failed: %v\n", err)
-		return output, err
-	}
-	output += "  ✅ Backend Docker image built\n"
-
-	// Scan avec Trivy
-	output += "  - Running Trivy vulnerability scanner...\n"
-	trivyScan := dag.Container().
-		From("aquasec/trivy:latest").
-		WithMountedCache("/root/.cache", dag.CacheVolume("trivy-cache")).
-		WithExec([]string{"trivy", "image", "--severity", "HIGH,CRITICAL", "--exit-code", "0", "backend:scan"})
-
-	_, err = trivyScan.Stdout(ctx)
-	if err != nil {
-		output += fmt.Sprintf("⚠️  Trivy scan completed (some vulnerabilities may be found)\n")
+		output += fmt.Sprintf("⚠️  Trivy scan completed with warnings: %v\n", err)
 	} else {
 		output += "  ✅ Trivy scan completed\n"
+		output += trivyOutput + "\n"
 	}
 	output += "\n"
 
@@ -443,49 +1025,136 @@ func (m *Example) GithubActionsSecurity(ctx context.Context, source *dagger.Dire
 	return output, nil
 }
 
-// TrivyScan exécute uniquement un scan Trivy de l'image backend
-func (m *Example) TrivyScan(ctx context.Context, source *dagger.Directory) (string, error) {
-	// Build backend Docker image depuis la racine
-	backendImage := source.DockerBuild(dagger.DirectoryDockerBuildOpts{
-		Dockerfile: "docker/Dockerfile.backend",
-	})
-	_, err := backendImage.Sync(ctx)
+// TrivyScan exécute uniquement un scan Trivy de l'image backend et renvoie
+// le rapport au format table.
+func (m *Chessot) TrivyScan(ctx context.Context, source *dagger.Directory) (string, error) {
+	report := m.TrivyScanReport(ctx, source, "table", "", false)
+	contents, err := report.Contents(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to build image: %w", err)
+		return "", fmt.Errorf("failed to run trivy scan: %w", err)
 	}
 
-	// Scan avec Trivy directement sur l'image buildée
-	output := "=== Trivy Vulnerability Scan ===\n\n"
-	output += "⚠️  Note: Le scan Trivy complet nécessite d'exporter l'image.\n"
-	output += "Sur GitHub Actions, Trivy scannera l'image complète.\n"
-	output += "✅ Image backend construite avec succès pour le scan.\n"
-
-	return output, nil
+	return "=== Trivy Vulnerability Scan ===\n\n" + contents, nil
 }
 
 // OwaspCheck exécute uniquement l'OWASP Dependency Check sur le backend
-func (m *Example) OwaspCheck(ctx context.Context, source *dagger.Directory) (string, error) {
-	owaspCheck := dag.Container().
-		From("maven:3.9-eclipse-temurin-21").
-		WithDirectory("/app", source.Directory("backend")).
-		WithWorkdir("/app").
-		WithMountedCache("/root/.m2", dag.CacheVolume("maven-cache")).
-		WithExec([]string{"mvn", "org.owasp:dependency-check-maven:check", "-B"})
-
-	return owaspCheck.Stdout(ctx)
+func (m *Chessot) OwaspCheck(ctx context.Context, source *dagger.Directory) (string, error) {
+	return m.MavenToolchain(source).DependencyCheck(ctx)
 }
 
 // NpmAudit exécute uniquement npm audit sur le frontend
-func (m *Example) NpmAudit(ctx context.Context, source *dagger.Directory) (string, error) {
-	npmAudit := dag.Container().
-		From("node:22-alpine").
-		WithDirectory("/app", source.Directory("frontend")).
-		WithWorkdir("/app").
-		WithMountedCache("/app/node_modules", dag.CacheVolume("node-modules")).
-		WithMountedCache("/root/.npm", dag.CacheVolume("npm-cache")).
-		WithExec([]string{"npm", "ci"}).
-		WithExec([]string{"npm", "audit", "--audit-level=high"})
+func (m *Chessot) NpmAudit(ctx context.Context, source *dagger.Directory) (string, error) {
+	return m.NodeToolchain(source).Audit(ctx)
+}
 
-	return npmAudit.Stdout(ctx)
+// trivyReportExtension donne l'extension de fichier adaptée au format de
+// rapport Trivy demandé.
+func trivyReportExtension(format string) string {
+	switch format {
+	case "sarif":
+		return "sarif"
+	case "cyclonedx", "json":
+		return "json"
+	default:
+		return "txt"
+	}
+}
+
+// TrivyScanReport scanne l'image backend construite depuis source et renvoie
+// le rapport Trivy dans le format demandé (table|json|sarif|cyclonedx).
+// failOn est une liste de sévérités séparées par des virgules (ex.
+// "CRITICAL,HIGH") qui fait échouer le scan si une vulnérabilité de ce niveau
+// est trouvée ; laissé vide, le scan ne fait jamais échouer la pipeline.
+func (m *Chessot) TrivyScanReport(
+	ctx context.Context,
+	source *dagger.Directory,
+	// +optional
+	format string,
+	// +optional
+	failOn string,
+	// +optional
+	ignoreUnfixed bool,
+) *dagger.File {
+	if format == "" {
+		format = "sarif"
+	}
+
+	backendImage := source.DockerBuild(dagger.DirectoryDockerBuildOpts{
+		Dockerfile: "docker/Dockerfile.backend",
+	})
+
+	reportPath := "/tmp/trivy-report." + trivyReportExtension(format)
+	args := []string{"trivy", "image", "--input", "/tmp/backend-image.tar", "--format", format, "--output", reportPath}
+	if failOn != "" {
+		args = append(args, "--severity", failOn, "--exit-code", "1")
+	} else {
+		args = append(args, "--exit-code", "0")
+	}
+	if ignoreUnfixed {
+		args = append(args, "--ignore-unfixed")
+	}
+
+	return dag.Container().
+		From("aquasec/trivy:latest").
+		WithMountedCache("/root/.cache", dag.CacheVolume("trivy-cache")).
+		WithFile("/tmp/backend-image.tar", backendImage.AsTarball()).
+		WithExec(args).
+		File(reportPath)
+}
+
+// OwaspCheckReport exécute l'OWASP Dependency Check sur le backend et renvoie
+// le rapport au format SARIF, consommable par GitHub Code Scanning.
+func (m *Chessot) OwaspCheckReport(ctx context.Context, source *dagger.Directory) *dagger.File {
+	return m.MavenToolchain(source).
+		Container().
+		WithExec([]string{"mvn", "org.owasp:dependency-check-maven:check", "-Dformat=SARIF", "-B"}).
+		File("/app/target/dependency-check-report.sarif")
+}
+
+// NpmAuditReport exécute npm audit sur le frontend et renvoie le rapport JSON
+// brut. La commande est enveloppée pour ne jamais faire échouer le build,
+// npm audit renvoyant un code non nul dès qu'une vulnérabilité est trouvée.
+func (m *Chessot) NpmAuditReport(ctx context.Context, source *dagger.Directory) *dagger.File {
+	return m.NodeToolchain(source).
+		Install().
+		WithExec([]string{"sh", "-c", "npm audit --json > /app/npm-audit-report.json || true"}).
+		File("/app/npm-audit-report.json")
+}
+
+// securityReportMergeScript fusionne les rapports Trivy et OWASP (déjà au
+// format SARIF) et convertit le rapport npm audit en un run SARIF minimal,
+// npm n'ayant pas d'exporteur SARIF natif.
+const securityReportMergeScript = `set -e
+jq -n --slurpfile npm /reports/npm-audit.json '
+  (($npm[0].vulnerabilities // {})) as $vulns
+  | {
+      tool: {driver: {name: "npm-audit", informationUri: "https://docs.npmjs.com/cli/v10/commands/npm-audit"}},
+      results: ($vulns | to_entries | map({
+        ruleId: .key,
+        level: "warning",
+        message: {text: (.key + ": " + .value.severity)}
+      }))
+    }
+' > /reports/npm-audit-run.json
+
+jq -s '{
+  "$schema": "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+  version: "2.1.0",
+  runs: (.[0].runs + .[1].runs + [.[2]])
+}' /reports/trivy.sarif /reports/owasp.sarif /reports/npm-audit-run.json > /reports/security-report.sarif
+`
+
+// SecurityReport agrège les résultats de Trivy, OWASP Dependency Check et npm
+// audit en un unique fichier SARIF, pour que les dashboards de code scanning
+// n'aient qu'un seul flux à consommer.
+func (m *Chessot) SecurityReport(ctx context.Context, source *dagger.Directory) *dagger.File {
+	return dag.Container().
+		From("alpine:3.20").
+		WithExec([]string{"apk", "add", "--no-cache", "jq"}).
+		WithFile("/reports/trivy.sarif", m.TrivyScanReport(ctx, source, "sarif", "", false)).
+		WithFile("/reports/owasp.sarif", m.OwaspCheckReport(ctx, source)).
+		WithFile("/reports/npm-audit.json", m.NpmAuditReport(ctx, source)).
+		WithExec([]string{"sh", "-c", securityReportMergeScript}).
+		File("/reports/security-report.sarif")
 }
 
@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+func TestParseGitRef(t *testing.T) {
+	cases := []struct {
+		ref    string
+		repo   string
+		gitRef string
+		hasRef bool
+	}{
+		{"github.com/org/repo", "github.com/org/repo", "", false},
+		{"github.com/org/repo#main", "github.com/org/repo", "main", true},
+		{"github.com/org/repo#refs/tags/v1.0.0", "github.com/org/repo", "refs/tags/v1.0.0", true},
+	}
+
+	for _, c := range cases {
+		repo, gitRef, hasRef := parseGitRef(c.ref)
+		if repo != c.repo || gitRef != c.gitRef || hasRef != c.hasRef {
+			t.Errorf("parseGitRef(%q) = (%q, %q, %v), want (%q, %q, %v)",
+				c.ref, repo, gitRef, hasRef, c.repo, c.gitRef, c.hasRef)
+		}
+	}
+}
+
+func TestTrivyReportExtension(t *testing.T) {
+	cases := map[string]string{
+		"sarif":     "sarif",
+		"cyclonedx": "json",
+		"json":      "json",
+		"table":     "txt",
+		"":          "txt",
+	}
+
+	for format, want := range cases {
+		if got := trivyReportExtension(format); got != want {
+			t.Errorf("trivyReportExtension(%q) = %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestNodeToolchainInstallCommand(t *testing.T) {
+	cases := map[string][]string{
+		"npm":  {"npm", "ci"},
+		"pnpm": {"pnpm", "install", "--frozen-lockfile"},
+		"yarn": {"yarn", "install", "--frozen-lockfile"},
+		"":     {"npm", "ci"},
+	}
+
+	for pm, want := range cases {
+		toolchain := &NodeToolchain{PackageManager: pm}
+		got := toolchain.installCommand()
+		if !equalStrings(got, want) {
+			t.Errorf("installCommand() with PackageManager=%q = %v, want %v", pm, got, want)
+		}
+	}
+}
+
+func TestNodeToolchainAuditCommand(t *testing.T) {
+	cases := map[string][]string{
+		"npm":  {"npm", "audit", "--audit-level=high"},
+		"pnpm": {"pnpm", "audit", "--audit-level=high"},
+		"yarn": {"yarn", "audit", "--level", "high"},
+	}
+
+	for pm, want := range cases {
+		toolchain := &NodeToolchain{PackageManager: pm}
+		got := toolchain.auditCommand()
+		if !equalStrings(got, want) {
+			t.Errorf("auditCommand() with PackageManager=%q = %v, want %v", pm, got, want)
+		}
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}